@@ -0,0 +1,94 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types reported on MongoDBStatus.Conditions.
+const (
+	// ConditionTypeInitialized is True once the MongoDB resource has been
+	// accepted by the operator and initial reconciliation has started.
+	ConditionTypeInitialized = "Initialized"
+
+	// ConditionTypeAvailable is True when every replica of the underlying
+	// StatefulSet is ready and on the current revision. It is derived from
+	// StatefulSet readiness only; no connection to a primary is attempted.
+	ConditionTypeAvailable = "Available"
+
+	// ConditionTypeHealthy is True when every replica of the underlying
+	// StatefulSet is ready and on the current revision. It is derived from
+	// StatefulSet readiness only and does not reflect the automation agent's
+	// view of member health.
+	ConditionTypeHealthy = "Healthy"
+
+	// ConditionTypeStatefulSetReady is True when the underlying StatefulSet
+	// has ReadyReplicas == Replicas and is on the current revision.
+	ConditionTypeStatefulSetReady = "StatefulSetReady"
+
+	// ConditionTypeReconcileSuccess is True when the most recent reconcile
+	// completed without error. When False, Reason and Message describe the
+	// failure.
+	ConditionTypeReconcileSuccess = "ReconcileSuccess"
+)
+
+// GetCondition returns the condition with the given type, or nil if it
+// isn't present.
+func (m *MongoDBStatus) GetCondition(condType string) *metav1.Condition {
+	for i := range m.Conditions {
+		if m.Conditions[i].Type == condType {
+			return &m.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// UpdateCondition sets the given condition, updating LastTransitionTime only
+// when the status actually changes. Conditions are added if not already
+// present.
+func (m *MongoDBStatus) UpdateCondition(condType string, status metav1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	existing := m.GetCondition(condType)
+	if existing == nil {
+		m.Conditions = append(m.Conditions, metav1.Condition{
+			Type:               condType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: now,
+		})
+		return
+	}
+
+	if existing.Status != status {
+		existing.LastTransitionTime = now
+	}
+	existing.Status = status
+	existing.Reason = reason
+	existing.Message = message
+}
+
+// HasConditionTrue returns true if the condition with the given type is
+// present and has status True.
+func (m MongoDBStatus) HasConditionTrue(condType string) bool {
+	for _, c := range m.Conditions {
+		if c.Type == condType {
+			return c.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// UpdatePhase derives the legacy Phase field from the current set of
+// conditions, preserving backwards compatibility for clients that have not
+// migrated to Conditions yet.
+func (m *MongoDBStatus) UpdatePhase() {
+	if rs := m.GetCondition(ConditionTypeReconcileSuccess); rs != nil && rs.Status == metav1.ConditionFalse {
+		m.Phase = Failed
+		return
+	}
+	if m.HasConditionTrue(ConditionTypeAvailable) && m.HasConditionTrue(ConditionTypeStatefulSetReady) {
+		m.Phase = Running
+		return
+	}
+	m.Phase = Pending
+}
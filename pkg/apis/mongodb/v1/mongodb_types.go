@@ -0,0 +1,129 @@
+// Package v1 contains API Schema definitions for the mongodb v1 API group
+// +kubebuilder:object:generate=true
+// +groupName=mongodb.com
+package v1
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version used to register these objects
+var SchemeGroupVersion = schema.GroupVersion{Group: "mongodb.com", Version: "v1"}
+
+// ResourceType defines the type of MongoDB deployment that the operator manages
+type ResourceType string
+
+const (
+	ReplicaSet ResourceType = "ReplicaSet"
+)
+
+// Phase indicates the status of the MongoDB resource
+type Phase string
+
+const (
+	Running Phase = "Running"
+	Failed  Phase = "Failed"
+	Pending Phase = "Pending"
+)
+
+// MongoDBSpec defines the desired state of MongoDB
+type MongoDBSpec struct {
+	// Members is the number of members in the replica set
+	Members int `json:"members"`
+
+	// Type defines which type of MongoDB deployment the resource should create
+	Type ResourceType `json:"type"`
+
+	// Version defines which version of MongoDB will be used
+	Version string `json:"version"`
+
+	// FeatureCompatibilityVersion configures the feature compatibility version that will
+	// be set for the deployment
+	// +optional
+	FeatureCompatibilityVersion string `json:"featureCompatibilityVersion,omitempty"`
+
+	// UpdateStrategy is passed through to the underlying StatefulSet,
+	// allowing e.g. a partitioned RollingUpdate so that only pods at or
+	// above a given ordinal are updated.
+	// +optional
+	UpdateStrategy appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// OwnerReferences allows this MongoDB resource to be placed under the
+	// control of a higher-level owning resource, e.g. a GitOps CR or a
+	// composite cluster resource. The operator passes these through to the
+	// StatefulSet it manages but never sets or removes them itself.
+	// +optional
+	OwnerReferences []metav1.OwnerReference `json:"ownerReferences,omitempty"`
+}
+
+// MongoDBStatus defines the observed state of MongoDB
+type MongoDBStatus struct {
+	// MongoURI is the connection string for this deployment
+	MongoURI string `json:"mongoUri"`
+
+	// Phase indicates the current lifecycle phase of this resource.
+	//
+	// Deprecated: Phase is derived from Conditions and is kept for backwards
+	// compatibility. New code should inspect Conditions instead.
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Conditions holds the current service state of the MongoDB deployment.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// MongoDB is the Schema for the mongodb API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type MongoDB struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MongoDBSpec   `json:"spec,omitempty"`
+	Status MongoDBStatus `json:"status,omitempty"`
+}
+
+// MongoURI returns the mongo uri that can be used to connect to this deployment
+func (m MongoDB) MongoURI() string {
+	return m.Status.MongoURI
+}
+
+// AutomationConfigSecretName returns the name of the secret which holds the automation config
+func (m MongoDB) AutomationConfigSecretName() string {
+	return fmt.Sprintf("%s-config", m.Name)
+}
+
+// ServiceName returns the name of the headless Service that provides the
+// per-pod DNS entries used by the StatefulSet.
+func (m MongoDB) ServiceName() string {
+	return fmt.Sprintf("%s-svc", m.Name)
+}
+
+// Hosts returns the per-pod DNS names the operator registers as replica set
+// members in the automation config. This is the single source of truth for
+// that naming scheme; anything that needs to predict a member's hostname
+// (the reconciler building the automation config, or a test asserting on
+// it) should call this instead of reconstructing the pattern itself.
+func (m MongoDB) Hosts() []string {
+	hostnames := make([]string, m.Spec.Members)
+	for i := range hostnames {
+		hostnames[i] = fmt.Sprintf("%s-%d.%s.%s.svc.cluster.local", m.Name, i, m.ServiceName(), m.Namespace)
+	}
+	return hostnames
+}
+
+// MongoDBList contains a list of MongoDB
+type MongoDBList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MongoDB `json:"items"`
+}
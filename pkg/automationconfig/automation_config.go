@@ -0,0 +1,45 @@
+// Package automationconfig models the MongoDB Automation Config document
+// that the operator generates and writes to the automation config Secret.
+package automationconfig
+
+// AutomationConfig is the document consumed by the automation agents running
+// inside each pod. It is kept intentionally close to the real document shape
+// so that it can be marshalled/unmarshalled without loss.
+type AutomationConfig struct {
+	Version     int          `json:"version"`
+	Processes   []Process    `json:"processes"`
+	ReplicaSets []ReplicaSet `json:"replicaSets"`
+
+	// FeatureCompatibilityVersion is bumped on major-version upgrades and
+	// pinned to the old value during a downgrade window.
+	FeatureCompatibilityVersion string `json:"featureCompatibilityVersion,omitempty"`
+
+	TLSConfig TLSConfig `json:"tls,omitempty"`
+}
+
+// Process describes a single mongod process managed by the automation agent.
+type Process struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	Version  string `json:"version"`
+}
+
+// ReplicaSet describes a replica set and its members as seen by the
+// automation agent.
+type ReplicaSet struct {
+	ID      string             `json:"_id"`
+	Members []ReplicaSetMember `json:"members"`
+}
+
+// ReplicaSetMember is a single member of a ReplicaSet.
+type ReplicaSetMember struct {
+	ID   int    `json:"_id"`
+	Host string `json:"host"`
+}
+
+// TLSConfig describes the TLS settings applied to every process in the
+// deployment.
+type TLSConfig struct {
+	Enabled    bool   `json:"enabled"`
+	CAFilePath string `json:"CAFilePath,omitempty"`
+}
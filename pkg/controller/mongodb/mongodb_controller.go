@@ -0,0 +1,118 @@
+// Package mongodb contains the reconciler for the MongoDB custom resource.
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	mdbv1 "github.com/mongodb/mongodb-kubernetes-operator/pkg/apis/mongodb/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AutomationConfigKey is the key under which the generated automation config
+// is stored in the automation config Secret.
+const AutomationConfigKey = "cluster-config.json"
+
+// PauseReconciliationAnnotation, when set to "true" on the MongoDB resource,
+// tells the operator to skip reconciliation entirely.
+const PauseReconciliationAnnotation = "mongodb.com/v1.pause"
+
+// ReconcileMongoDB reconciles a MongoDB object.
+type ReconcileMongoDB struct {
+	client client.Client
+}
+
+// Reconcile reads the state of the cluster for a MongoDB object and makes
+// changes to bring it in line with the desired state described in the spec.
+func (r *ReconcileMongoDB) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	mdb := mdbv1.MongoDB{}
+	if err := r.client.Get(context.TODO(), request.NamespacedName, &mdb); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if mdb.GetAnnotations()[PauseReconciliationAnnotation] == "true" {
+		return reconcile.Result{}, nil
+	}
+
+	mdb.Status.UpdateCondition(mdbv1.ConditionTypeInitialized, metav1.ConditionTrue, "Initialized", "")
+
+	if err := r.ensureStatefulSet(&mdb); err != nil {
+		reason := "ReconcileFailed"
+		if _, ok := err.(errForeignController); ok {
+			reason = "ForeignController"
+		}
+		mdb.Status.UpdateCondition(mdbv1.ConditionTypeReconcileSuccess, metav1.ConditionFalse, reason, err.Error())
+		mdb.Status.UpdatePhase()
+		_ = r.client.Status().Update(context.TODO(), &mdb)
+		return reconcile.Result{}, err
+	}
+
+	r.updateStatefulSetReadyCondition(&mdb)
+	mdb.Status.UpdateCondition(mdbv1.ConditionTypeReconcileSuccess, metav1.ConditionTrue, "ReconcileSucceeded", "")
+	mdb.Status.UpdatePhase()
+
+	if err := r.client.Status().Update(context.TODO(), &mdb); err != nil {
+		return reconcile.Result{}, fmt.Errorf("error updating MongoDB status: %s", err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// updateStatefulSetReadyCondition sets the StatefulSetReady and Available
+// conditions based on the current state of the underlying StatefulSet.
+func (r *ReconcileMongoDB) updateStatefulSetReadyCondition(mdb *mdbv1.MongoDB) {
+	sts := appsv1.StatefulSet{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, &sts)
+	if err != nil {
+		mdb.Status.UpdateCondition(mdbv1.ConditionTypeStatefulSetReady, metav1.ConditionFalse, "StatefulSetNotFound", err.Error())
+		mdb.Status.UpdateCondition(mdbv1.ConditionTypeAvailable, metav1.ConditionFalse, "StatefulSetNotFound", err.Error())
+		return
+	}
+
+	ready := sts.Status.ReadyReplicas == sts.Status.Replicas &&
+		sts.Status.UpdateRevision == sts.Status.CurrentRevision
+
+	if ready {
+		mdb.Status.UpdateCondition(mdbv1.ConditionTypeStatefulSetReady, metav1.ConditionTrue, "Ready", "")
+		mdb.Status.UpdateCondition(mdbv1.ConditionTypeAvailable, metav1.ConditionTrue, "Ready", "")
+		mdb.Status.UpdateCondition(mdbv1.ConditionTypeHealthy, metav1.ConditionTrue, "Ready", "")
+		return
+	}
+
+	mdb.Status.UpdateCondition(mdbv1.ConditionTypeStatefulSetReady, metav1.ConditionFalse, "StatefulSetNotReady",
+		fmt.Sprintf("%d/%d replicas ready", sts.Status.ReadyReplicas, sts.Status.Replicas))
+	mdb.Status.UpdateCondition(mdbv1.ConditionTypeAvailable, metav1.ConditionFalse, "StatefulSetNotReady", "not all replicas are ready")
+	mdb.Status.UpdateCondition(mdbv1.ConditionTypeHealthy, metav1.ConditionFalse, "StatefulSetNotReady", "not all replicas are ready")
+}
+
+// ensureStatefulSet creates or updates the StatefulSet backing this MongoDB
+// resource. The construction of the StatefulSet object itself lives
+// elsewhere in this package and is intentionally not duplicated here.
+func (r *ReconcileMongoDB) ensureStatefulSet(mdb *mdbv1.MongoDB) error {
+	sts := appsv1.StatefulSet{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, &sts)
+	notFound := apierrors.IsNotFound(err)
+	if err != nil && !notFound {
+		return err
+	}
+
+	controllerRef := *metav1.NewControllerRef(mdb, mdbv1.SchemeGroupVersion.WithKind("MongoDB"))
+	refs, err := setOwnerReferences(mdb, controllerRef, sts.GetOwnerReferences())
+	if err != nil {
+		return err
+	}
+	sts.SetOwnerReferences(refs)
+	sts.Spec.UpdateStrategy = mdb.Spec.UpdateStrategy
+
+	if notFound {
+		sts.Name = mdb.Name
+		sts.Namespace = mdb.Namespace
+		return r.client.Create(context.TODO(), &sts)
+	}
+	return r.client.Update(context.TODO(), &sts)
+}
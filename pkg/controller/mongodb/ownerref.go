@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"fmt"
+
+	mdbv1 "github.com/mongodb/mongodb-kubernetes-operator/pkg/apis/mongodb/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// errForeignController is returned when a child object already has a
+// controller owner reference that does not belong to this operator.
+type errForeignController struct {
+	owner metav1.OwnerReference
+}
+
+func (e errForeignController) Error() string {
+	return fmt.Sprintf("refusing to adopt: object already has controller owner %s/%s", e.owner.Kind, e.owner.Name)
+}
+
+// shouldAdopt reports whether it is safe for the operator to stamp
+// controllerRef onto existingRefs. Adoption is skipped only when a
+// *different* controller already owns the object, mirroring the adoption
+// check used for other built-in controllers (e.g. ReplicaSet adopting
+// Pods) so that the operator never steals an object from a different
+// owner. A controller ref that already identifies controllerRef itself
+// (e.g. on every reconcile after the first) is not foreign and does not
+// block adoption.
+func shouldAdopt(controllerRef metav1.OwnerReference, existingRefs []metav1.OwnerReference) (bool, *metav1.OwnerReference) {
+	for i := range existingRefs {
+		ref := existingRefs[i]
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.UID == controllerRef.UID {
+			continue
+		}
+		return false, &ref
+	}
+	return true, nil
+}
+
+// setOwnerReferences merges the operator's controller OwnerReference and any
+// user-supplied mdb.Spec.OwnerReferences into existingRefs and returns the
+// result; other references already in existingRefs are kept. If existingRefs
+// already has a foreign controller reference, errForeignController is
+// returned together with existingRefs unchanged.
+func setOwnerReferences(mdb *mdbv1.MongoDB, controllerRef metav1.OwnerReference, existingRefs []metav1.OwnerReference) ([]metav1.OwnerReference, error) {
+	ok, foreign := shouldAdopt(controllerRef, existingRefs)
+	if !ok {
+		return existingRefs, errForeignController{owner: *foreign}
+	}
+
+	refs := append([]metav1.OwnerReference{controllerRef}, mdb.Spec.OwnerReferences...)
+	for _, existing := range existingRefs {
+		present := false
+		for _, ref := range refs {
+			if ref.UID == existing.UID {
+				present = true
+				break
+			}
+		}
+		if !present {
+			refs = append(refs, existing)
+		}
+	}
+	return refs, nil
+}
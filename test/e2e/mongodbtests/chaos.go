@@ -0,0 +1,222 @@
+package mongodbtests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mdbv1 "github.com/mongodb/mongodb-kubernetes-operator/pkg/apis/mongodb/v1"
+	"github.com/mongodb/mongodb-kubernetes-operator/pkg/automationconfig"
+	"github.com/mongodb/mongodb-kubernetes-operator/pkg/controller/mongodb"
+	e2eutil "github.com/mongodb/mongodb-kubernetes-operator/test/e2e"
+	f "github.com/operator-framework/operator-sdk/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DeleteAllPods deletes every pod belonging to mdb's StatefulSet in
+// parallel and asserts the set fully recovers: all replicas come back
+// ready, a primary is re-elected, and the automation config version is
+// left unchanged by the disruption.
+func DeleteAllPods(mdb *mdbv1.MongoDB) func(t *testing.T) {
+	return func(t *testing.T) {
+		currentAc := getAutomationConfigVersion(t, mdb)
+
+		var wg sync.WaitGroup
+		for i := 0; i < mdb.Spec.Members; i++ {
+			wg.Add(1)
+			go func(podNum int) {
+				defer wg.Done()
+				pod := corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("%s-%d", mdb.Name, podNum),
+						Namespace: mdb.Namespace,
+					},
+				}
+				if err := f.Global.Client.Delete(context.TODO(), &pod); err != nil {
+					t.Errorf("error deleting pod %s: %s", pod.Name, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		t.Run("StatefulSet Recovers", StatefulSetIsReady(mdb))
+		t.Run("StatefulSetReady Condition Recovers", WaitForCondition(mdb, mdbv1.ConditionTypeStatefulSetReady, metav1.ConditionTrue, time.Minute*5))
+		t.Run("Healthy Condition Recovers", WaitForCondition(mdb, mdbv1.ConditionTypeHealthy, metav1.ConditionTrue, time.Minute*5))
+		// A w:majority write only succeeds once a primary has been elected.
+		t.Run("Primary Is Re-elected", MongoDBDataIsPersisted(mdb, "delete-all-pods", "primary-re-elected"))
+		t.Run("Automation Config Version Unchanged", AutomationConfigVersionHasTheExpectedVersion(mdb, currentAc))
+	}
+}
+
+// PartitionedRollingUpgrade sets spec.updateStrategy.rollingUpdate.partition
+// and bumps spec.version, then asserts that only pods at ordinal >=
+// partition are updated while pods below the partition stay on the old
+// image, mirroring a manual/partitioned StatefulSet rolling upgrade.
+func PartitionedRollingUpgrade(mdb *mdbv1.MongoDB, partition int32, newVersion string) func(t *testing.T) {
+	return func(t *testing.T) {
+		oldVersion := mdb.Spec.Version
+
+		err := e2eutil.UpdateMongoDBResource(mdb, func(db *mdbv1.MongoDB) {
+			db.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+					Partition: &partition,
+				},
+			}
+			db.Spec.Version = newVersion
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("Pods Below Partition Stay On Old Version", func(t *testing.T) {
+			err := wait.Poll(time.Second*15, time.Minute*10, func() (bool, error) {
+				for i := 0; i < int(partition); i++ {
+					pod := corev1.Pod{}
+					if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-%d", mdb.Name, i), Namespace: mdb.Namespace}, &pod); err != nil {
+						return false, nil
+					}
+					if !podHasVersion(pod, oldVersion) {
+						return false, fmt.Errorf("pod %s was updated below the partition", pod.Name)
+					}
+				}
+				for i := int(partition); i < mdb.Spec.Members; i++ {
+					pod := corev1.Pod{}
+					if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-%d", mdb.Name, i), Namespace: mdb.Namespace}, &pod); err != nil {
+						return false, nil
+					}
+					if !podHasVersion(pod, newVersion) {
+						return false, nil
+					}
+				}
+				return true, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func podHasVersion(pod corev1.Pod, version string) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "mongod" {
+			return strings.HasSuffix(c.Image, ":"+version)
+		}
+	}
+	return false
+}
+
+// PauseAndResumeReconciliation sets the pause annotation on mdb and asserts
+// that the underlying StatefulSet is left untouched by spec changes made
+// while paused, then removes the annotation and waits for the StatefulSet to
+// report all replicas ready. It does not check that the spec change made
+// while paused is applied after the annotation is removed.
+func PauseAndResumeReconciliation(mdb *mdbv1.MongoDB) func(t *testing.T) {
+	return func(t *testing.T) {
+		sts := appsv1.StatefulSet{}
+		if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, &sts); err != nil {
+			t.Fatal(err)
+		}
+		generationBeforePause := sts.Generation
+
+		err := e2eutil.UpdateMongoDBResource(mdb, func(db *mdbv1.MongoDB) {
+			annotations := db.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[mongodb.PauseReconciliationAnnotation] = "true"
+			db.SetAnnotations(annotations)
+			db.Spec.Members = db.Spec.Members + 2
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("StatefulSet Is Not Changed While Paused", func(t *testing.T) {
+			time.Sleep(time.Second * 30)
+			current := appsv1.StatefulSet{}
+			if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, &current); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, generationBeforePause, current.Generation, "StatefulSet should not have been touched while reconciliation is paused")
+		})
+
+		err = e2eutil.UpdateMongoDBResource(mdb, func(db *mdbv1.MongoDB) {
+			annotations := db.GetAnnotations()
+			delete(annotations, mongodb.PauseReconciliationAnnotation)
+			db.SetAnnotations(annotations)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("StatefulSet Is Ready After Resume", StatefulSetIsReady(mdb))
+	}
+}
+
+// AssertReplicaSetMembership connects to MongoDB via the operator-generated
+// URI and reads rs.status() to validate that the Kubernetes-visible pods
+// match the actual replica-set configuration as seen by MongoDB itself.
+func AssertReplicaSetMembership(mdb *mdbv1.MongoDB, expectedMembers []string) func(t *testing.T) {
+	return func(t *testing.T) {
+		members, err := replicaSetMembers(mdb.MongoURI())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.ElementsMatch(t, expectedMembers, members)
+	}
+}
+
+func getAutomationConfigVersion(t *testing.T, mdb *mdbv1.MongoDB) int {
+	t.Helper()
+	currentSecret := corev1.Secret{}
+	if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.AutomationConfigSecretName(), Namespace: mdb.Namespace}, &currentSecret); err != nil {
+		t.Fatal(err)
+	}
+	currentAc := automationconfig.AutomationConfig{}
+	if err := json.Unmarshal(currentSecret.Data[mongodb.AutomationConfigKey], &currentAc); err != nil {
+		t.Fatal(err)
+	}
+	return currentAc.Version
+}
+
+// replicaSetMembers connects to the deployment at uri and returns the host
+// names of every member reported by rs.status().
+func replicaSetMembers(uri string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %s", uri, err)
+	}
+	defer client.Disconnect(ctx)
+
+	var status struct {
+		Members []struct {
+			Name string `bson:"name"`
+		} `bson:"members"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}).Decode(&status); err != nil {
+		return nil, fmt.Errorf("error running replSetGetStatus: %s", err)
+	}
+
+	members := make([]string, len(status.Members))
+	for i, m := range status.Members {
+		members[i] = m.Name
+	}
+	return members, nil
+}
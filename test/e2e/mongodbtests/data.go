@@ -0,0 +1,161 @@
+package mongodbtests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mdbv1 "github.com/mongodb/mongodb-kubernetes-operator/pkg/apis/mongodb/v1"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// dataTestDatabase/dataTestCollection are the well-known locations the data
+// assertion helpers write to and read from.
+const (
+	dataTestDatabase   = "e2e-data-assertions"
+	dataTestCollection = "values"
+)
+
+// dataOptions configures the optional behavior of the data assertion
+// helpers.
+type dataOptions struct {
+	readPreference *readpref.ReadPref
+}
+
+// DataOption configures a data assertion helper.
+type DataOption func(*dataOptions)
+
+// WithReadPreference configures the read preference used when reading data
+// back, allowing tests to validate that secondary reads also observe the
+// expected value.
+func WithReadPreference(rp *readpref.ReadPref) DataOption {
+	return func(o *dataOptions) {
+		o.readPreference = rp
+	}
+}
+
+// MongoDBDataIsPersisted opens a real mongo.Client against mdb.MongoURI()
+// using w:majority, upserts testKey/testValue into a well-known test
+// collection, and reads it straight back to confirm MongoDB itself is
+// reachable and functioning, not just that the Kubernetes objects have the
+// right shape.
+func MongoDBDataIsPersisted(mdb *mdbv1.MongoDB, testKey, testValue string, opts ...DataOption) func(t *testing.T) {
+	return func(t *testing.T) {
+		if err := writeTestData(mdb.MongoURI(), testKey, testValue); err != nil {
+			t.Fatal(err)
+		}
+		assertTestDataReadable(t, mdb.MongoURI(), testKey, testValue, opts...)
+	}
+}
+
+// ScaleAndVerifyData writes test data, scales mdb to newMembers, waits for
+// the resource to reach Running again, and reads the data back — proving
+// the reconciler preserves data across a scale-up or scale-down (member
+// removal).
+func ScaleAndVerifyData(mdb *mdbv1.MongoDB, newMembers int, testKey, testValue string, opts ...DataOption) func(t *testing.T) {
+	return func(t *testing.T) {
+		if err := writeTestData(mdb.MongoURI(), testKey, testValue); err != nil {
+			t.Fatal(err)
+		}
+		t.Run("Scale", Scale(mdb, newMembers))
+		t.Run("Reaches Running Phase", MongoDBReachesRunningPhase(mdb))
+		t.Run("Data Still Readable", func(t *testing.T) {
+			assertTestDataReadable(t, mdb.MongoURI(), testKey, testValue, opts...)
+		})
+	}
+}
+
+// ChangeVersionAndVerifyData writes test data, changes mdb's version,
+// waits for the resource to reach Running again, and reads the data back —
+// proving the reconciler preserves data across a rolling version upgrade.
+func ChangeVersionAndVerifyData(mdb *mdbv1.MongoDB, newVersion, testKey, testValue string, opts ...DataOption) func(t *testing.T) {
+	return func(t *testing.T) {
+		if err := writeTestData(mdb.MongoURI(), testKey, testValue); err != nil {
+			t.Fatal(err)
+		}
+		t.Run("Change Version", ChangeVersion(mdb, newVersion))
+		t.Run("Reaches Running Phase", MongoDBReachesRunningPhase(mdb))
+		t.Run("Data Still Readable", func(t *testing.T) {
+			assertTestDataReadable(t, mdb.MongoURI(), testKey, testValue, opts...)
+		})
+	}
+}
+
+// writeTestData upserts testKey/testValue into the test collection with
+// w:majority, retrying to tolerate the brief primary-step-down window that
+// can occur during rolling updates.
+func writeTestData(uri, testKey, testValue string) error {
+	return withRetry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetWriteConcern(writeconcern.New(writeconcern.WMajority())))
+		if err != nil {
+			return fmt.Errorf("error connecting to %s: %s", uri, err)
+		}
+		defer client.Disconnect(ctx)
+
+		coll := client.Database(dataTestDatabase).Collection(dataTestCollection)
+		_, err = coll.UpdateOne(ctx,
+			bson.M{"_id": testKey},
+			bson.M{"$set": bson.M{"value": testValue}},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	})
+}
+
+// assertTestDataReadable reads testKey back from the test collection and
+// asserts it equals testValue, retrying to tolerate the brief
+// primary-step-down window that can occur during rolling updates.
+func assertTestDataReadable(t *testing.T, uri, testKey, testValue string, opts ...DataOption) {
+	o := dataOptions{readPreference: readpref.Primary()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var got string
+	err := withRetry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetReadPreference(o.readPreference))
+		if err != nil {
+			return fmt.Errorf("error connecting to %s: %s", uri, err)
+		}
+		defer client.Disconnect(ctx)
+
+		coll := client.Database(dataTestDatabase).Collection(dataTestCollection)
+		var doc struct {
+			Value string `bson:"value"`
+		}
+		if err := coll.FindOne(ctx, bson.M{"_id": testKey}).Decode(&doc); err != nil {
+			return err
+		}
+		got = doc.Value
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, testValue, got)
+}
+
+// withRetry retries fn a handful of times with a short backoff, tolerating
+// the brief primary-step-down window during rolling updates and scaling.
+func withRetry(fn func() error) error {
+	var err error
+	for i := 0; i < 5; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		time.Sleep(time.Second * 5)
+	}
+	return err
+}
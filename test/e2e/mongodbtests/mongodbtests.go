@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -19,8 +20,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// expectedConditions are the condition types every healthy MongoDB
+// resource is expected to report.
+var expectedConditions = []string{
+	mdbv1.ConditionTypeInitialized,
+	mdbv1.ConditionTypeAvailable,
+	mdbv1.ConditionTypeHealthy,
+	mdbv1.ConditionTypeStatefulSetReady,
+	mdbv1.ConditionTypeReconcileSuccess,
+}
+
 // StatefulSetIsReady ensures that the underlying stateful set
 // reaches the running state
 func StatefulSetIsReady(mdb *mdbv1.MongoDB) func(t *testing.T) {
@@ -33,23 +45,83 @@ func StatefulSetIsReady(mdb *mdbv1.MongoDB) func(t *testing.T) {
 	}
 }
 
-func StatefulSetHasOwnerReference(mdb *mdbv1.MongoDB, expectedOwnerReference metav1.OwnerReference) func(t *testing.T) {
+// StatefulSetHasControllerRef asserts that the StatefulSet backing mdb has
+// the operator's expectedControllerRef as its sole controller
+// OwnerReference (Controller: true), while tolerating any number of
+// additional, non-controller OwnerReferences (e.g. a user-supplied
+// spec.ownerReferences passthrough).
+func StatefulSetHasControllerRef(mdb *mdbv1.MongoDB, expectedControllerRef metav1.OwnerReference) func(t *testing.T) {
 	return func(t *testing.T) {
 		sts := appsv1.StatefulSet{}
 		err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: f.Global.OperatorNamespace}, &sts)
 		if err != nil {
 			t.Fatal(err)
 		}
-		ownerReferences := sts.GetOwnerReferences()
 
-		assert.Len(t, ownerReferences, 1, "StatefulSet doesn't have OwnerReferences")
+		var controllerRefs []metav1.OwnerReference
+		for _, ref := range sts.GetOwnerReferences() {
+			if ref.Controller != nil && *ref.Controller {
+				controllerRefs = append(controllerRefs, ref)
+			}
+		}
+
+		if !assert.Len(t, controllerRefs, 1, "StatefulSet should have exactly one controller OwnerReference") {
+			return
+		}
+
+		assert.Equal(t, expectedControllerRef.APIVersion, controllerRefs[0].APIVersion)
+		assert.Equal(t, "MongoDB", controllerRefs[0].Kind)
+		assert.Equal(t, expectedControllerRef.Name, controllerRefs[0].Name)
+		assert.Equal(t, expectedControllerRef.UID, controllerRefs[0].UID)
+
+		t.Logf("StatefulSet %s/%s has the correct controller OwnerReference!", mdb.Namespace, mdb.Name)
+	}
+}
+
+// AdoptionSkippedWhenControllerRefExists pre-creates the StatefulSet backing
+// mdb with a foreign controller OwnerReference and asserts the operator
+// refuses to take it over, reporting ReconcileSuccess=False with reason
+// ForeignController instead of clobbering the existing owner.
+func AdoptionSkippedWhenControllerRefExists(mdb *mdbv1.MongoDB, foreignOwner metav1.OwnerReference) func(t *testing.T) {
+	return func(t *testing.T) {
+		isController := true
+		foreignOwner.Controller = &isController
+
+		sts := appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            mdb.Name,
+				Namespace:       f.Global.OperatorNamespace,
+				OwnerReferences: []metav1.OwnerReference{foreignOwner},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				ServiceName: mdb.Name,
+			},
+		}
+		if err := f.Global.Client.Create(context.TODO(), &sts, nil); err != nil {
+			t.Fatal(err)
+		}
 
-		assert.Equal(t, expectedOwnerReference.APIVersion, ownerReferences[0].APIVersion)
-		assert.Equal(t, "MongoDB", ownerReferences[0].Kind)
-		assert.Equal(t, expectedOwnerReference.Name, ownerReferences[0].Name)
-		assert.Equal(t, expectedOwnerReference.UID, ownerReferences[0].UID)
+		t.Run("ReconcileSuccess Condition Is False With Reason ForeignController", func(t *testing.T) {
+			err := wait.Poll(time.Second*5, time.Minute*2, func() (bool, error) {
+				if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, mdb); err != nil {
+					return false, nil
+				}
+				cond := mdb.Status.GetCondition(mdbv1.ConditionTypeReconcileSuccess)
+				return cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason == "ForeignController", nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
 
-		t.Logf("StatefulSet %s/%s has the correct OwnerReference!", mdb.Namespace, mdb.Name)
+		t.Run("StatefulSet Still Owned By Foreign Controller", func(t *testing.T) {
+			current := appsv1.StatefulSet{}
+			if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: f.Global.OperatorNamespace}, &current); err != nil {
+				t.Fatal(err)
+			}
+			assert.Len(t, current.GetOwnerReferences(), 1)
+			assert.Equal(t, foreignOwner.UID, current.GetOwnerReferences()[0].UID)
+		})
 	}
 }
 
@@ -90,16 +162,122 @@ func AutomationConfigSecretExists(mdb *mdbv1.MongoDB) func(t *testing.T) {
 
 func AutomationConfigVersionHasTheExpectedVersion(mdb *mdbv1.MongoDB, expectedVersion int) func(t *testing.T) {
 	return func(t *testing.T) {
-		currentSecret := corev1.Secret{}
-		currentAc := automationconfig.AutomationConfig{}
-		err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.AutomationConfigSecretName(), Namespace: mdb.Namespace}, &currentSecret)
-		assert.NoError(t, err)
-		err = json.Unmarshal(currentSecret.Data[mongodb.AutomationConfigKey], &currentAc)
-		assert.NoError(t, err)
+		currentAc := getCurrentAutomationConfig(t, mdb)
 		assert.Equal(t, expectedVersion, currentAc.Version)
 	}
 }
 
+// AutomationConfigMatches decodes the automation config Secret and asserts
+// it deep-equals expected, after masking out volatile fields (defaulting to
+// Version) and any additional ignoreFields. This catches regressions in the
+// generated config — e.g. a wrong TLS block, a missing replica-set member,
+// an incorrect processes list after a scale-down — that a bare version
+// check would silently let through.
+func AutomationConfigMatches(mdb *mdbv1.MongoDB, expected automationconfig.AutomationConfig, ignoreFields ...string) func(t *testing.T) {
+	return func(t *testing.T) {
+		actual := getCurrentAutomationConfig(t, mdb)
+		ignored := append([]string{"Version"}, ignoreFields...)
+		assert.Equal(t, maskAutomationConfigFields(expected, ignored), maskAutomationConfigFields(actual, ignored))
+	}
+}
+
+// AutomationConfigHasMembers asserts that the single replica set in the
+// automation config has exactly the given member host names.
+func AutomationConfigHasMembers(mdb *mdbv1.MongoDB, expectedNames []string) func(t *testing.T) {
+	return func(t *testing.T) {
+		currentAc := getCurrentAutomationConfig(t, mdb)
+		if !assert.Len(t, currentAc.ReplicaSets, 1, "expected exactly one replica set in the automation config") {
+			return
+		}
+
+		var actualNames []string
+		for _, m := range currentAc.ReplicaSets[0].Members {
+			actualNames = append(actualNames, m.Host)
+		}
+		assert.ElementsMatch(t, expectedNames, actualNames)
+	}
+}
+
+// AutomationConfigHasFCV asserts that the automation config's
+// FeatureCompatibilityVersion matches fcv.
+func AutomationConfigHasFCV(mdb *mdbv1.MongoDB, fcv string) func(t *testing.T) {
+	return func(t *testing.T) {
+		currentAc := getCurrentAutomationConfig(t, mdb)
+		assert.Equal(t, fcv, currentAc.FeatureCompatibilityVersion)
+	}
+}
+
+// AutomationConfigTLSEnabled asserts that TLS is enabled in the automation
+// config and that it references the given CA Secret.
+func AutomationConfigTLSEnabled(mdb *mdbv1.MongoDB, caSecret string) func(t *testing.T) {
+	return func(t *testing.T) {
+		currentAc := getCurrentAutomationConfig(t, mdb)
+		assert.True(t, currentAc.TLSConfig.Enabled, "expected TLS to be enabled")
+		assert.Contains(t, currentAc.TLSConfig.CAFilePath, caSecret)
+	}
+}
+
+// getCurrentAutomationConfig fetches and decodes the automation config
+// Secret for mdb.
+func getCurrentAutomationConfig(t *testing.T, mdb *mdbv1.MongoDB) automationconfig.AutomationConfig {
+	t.Helper()
+	currentSecret := corev1.Secret{}
+	err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.AutomationConfigSecretName(), Namespace: mdb.Namespace}, &currentSecret)
+	assert.NoError(t, err)
+	currentAc := automationconfig.AutomationConfig{}
+	err = json.Unmarshal(currentSecret.Data[mongodb.AutomationConfigKey], &currentAc)
+	assert.NoError(t, err)
+	return currentAc
+}
+
+// maskAutomationConfigFields returns a copy of ac with the named top-level
+// fields (e.g. "Version") zeroed out, so they can be excluded from an
+// equality comparison.
+func maskAutomationConfigFields(ac automationconfig.AutomationConfig, fields []string) automationconfig.AutomationConfig {
+	v := reflect.ValueOf(&ac).Elem()
+	for _, name := range fields {
+		field := v.FieldByName(name)
+		if field.IsValid() && field.CanSet() {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+	return ac
+}
+
+// MongoDBHasCondition asserts that the MongoDB resource currently reports
+// the given condition type and status.
+func MongoDBHasCondition(mdb *mdbv1.MongoDB, condType string, status metav1.ConditionStatus) func(t *testing.T) {
+	return func(t *testing.T) {
+		if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, mdb); err != nil {
+			t.Fatal(fmt.Errorf("error getting MongoDB resource: %+v", err))
+		}
+		cond := mdb.Status.GetCondition(condType)
+		if !assert.NotNil(t, cond, "expected condition %s to be set", condType) {
+			return
+		}
+		assert.Equal(t, status, cond.Status, "condition %s had unexpected status: %s", condType, cond.Message)
+	}
+}
+
+// WaitForCondition polls the MongoDB resource until it reports the given
+// condition with the expected status, failing the test if timeout elapses
+// first.
+func WaitForCondition(mdb *mdbv1.MongoDB, condType string, status metav1.ConditionStatus, timeout time.Duration) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := wait.Poll(time.Second*5, timeout, func() (bool, error) {
+			if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, mdb); err != nil {
+				return false, nil
+			}
+			cond := mdb.Status.GetCondition(condType)
+			return cond != nil && cond.Status == status, nil
+		})
+		if err != nil {
+			t.Fatalf("condition %s never reached status %s: %s", condType, status, err)
+		}
+		t.Logf("condition %s reached status %s", condType, status)
+	}
+}
+
 // CreateMongoDBResource creates the MongoDB resource
 func CreateMongoDBResource(mdb *mdbv1.MongoDB, ctx *f.Context) func(*testing.T) {
 	return func(t *testing.T) {
@@ -115,17 +293,19 @@ func BasicFunctionality(mdb *mdbv1.MongoDB) func(*testing.T) {
 		t.Run("Config Map Was Correctly Created", AutomationConfigSecretExists(mdb))
 		t.Run("Stateful Set Reaches Ready State", StatefulSetIsReady(mdb))
 		t.Run("MongoDB Reaches Running Phase", MongoDBReachesRunningPhase(mdb))
-		t.Run("Stateful Set has OwnerReference", StatefulSetHasOwnerReference(mdb,
+		t.Run("Stateful Set has OwnerReference", StatefulSetHasControllerRef(mdb,
 			*metav1.NewControllerRef(mdb, schema.GroupVersionKind{
 				Group:   mdbv1.SchemeGroupVersion.Group,
 				Version: mdbv1.SchemeGroupVersion.Version,
 				Kind:    mdb.Kind,
 			})))
-		t.Run("Test Status Was Updated", Status(mdb,
-			mdbv1.MongoDBStatus{
-				MongoURI: mdb.MongoURI(),
-				Phase:    mdbv1.Running,
-			}))
+		t.Run("MongoDB Reaches All Expected Conditions", func(t *testing.T) {
+			for _, condType := range expectedConditions {
+				t.Run(condType, MongoDBHasCondition(mdb, condType, metav1.ConditionTrue))
+			}
+		})
+		t.Run("Data Is Persisted", MongoDBDataIsPersisted(mdb, "basic-functionality", "it-works"))
+		t.Run("Automation Config Has Expected Members", AutomationConfigHasMembers(mdb, mdb.Hosts()))
 	}
 }
 
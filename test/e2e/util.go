@@ -0,0 +1,83 @@
+// Package e2e contains shared utilities used by the end-to-end test suites.
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mdbv1 "github.com/mongodb/mongodb-kubernetes-operator/pkg/apis/mongodb/v1"
+	f "github.com/operator-framework/operator-sdk/pkg/test"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// WaitForStatefulSetToBeReady waits until all replicas of the StatefulSet
+// backing mdb are ready, polling every interval until timeout elapses.
+func WaitForStatefulSetToBeReady(t *testing.T, mdb *mdbv1.MongoDB, interval, timeout time.Duration) error {
+	return wait.Poll(interval, timeout, func() (bool, error) {
+		sts := appsv1.StatefulSet{}
+		if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, &sts); err != nil {
+			return false, nil
+		}
+		t.Logf("waiting for StatefulSet %s/%s to be ready: %d/%d replicas ready",
+			mdb.Namespace, mdb.Name, sts.Status.ReadyReplicas, sts.Status.Replicas)
+		return sts.Status.ReadyReplicas == sts.Status.Replicas, nil
+	})
+}
+
+// WaitForStatefulSetToHaveUpdateStrategy waits until the StatefulSet backing
+// mdb reports the given update strategy.
+func WaitForStatefulSetToHaveUpdateStrategy(t *testing.T, mdb *mdbv1.MongoDB, strategy appsv1.StatefulSetUpdateStrategyType, interval, timeout time.Duration) error {
+	return wait.Poll(interval, timeout, func() (bool, error) {
+		sts := appsv1.StatefulSet{}
+		if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, &sts); err != nil {
+			return false, nil
+		}
+		return sts.Spec.UpdateStrategy.Type == strategy, nil
+	})
+}
+
+// WaitForMongoDBToReachPhase waits until mdb's status reports the given
+// phase.
+func WaitForMongoDBToReachPhase(t *testing.T, mdb *mdbv1.MongoDB, phase mdbv1.Phase, interval, timeout time.Duration) error {
+	return wait.Poll(interval, timeout, func() (bool, error) {
+		if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, mdb); err != nil {
+			return false, nil
+		}
+		t.Logf("waiting for phase %s, got %s", phase, mdb.Status.Phase)
+		return mdb.Status.Phase == phase, nil
+	})
+}
+
+// WaitForSecretToExist waits until the named Secret exists in the operator
+// namespace and returns it.
+func WaitForSecretToExist(secretName string, interval, timeout time.Duration) (corev1.Secret, error) {
+	secret := corev1.Secret{}
+	err := wait.Poll(interval, timeout, func() (bool, error) {
+		err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: f.Global.OperatorNamespace}, &secret)
+		if err != nil {
+			return false, nil
+		}
+		return true, nil
+	})
+	return secret, err
+}
+
+// UpdateMongoDBResource applies updateFunc to the latest version of mdb and
+// persists the change, retrying on conflict (the operator's own
+// Status().Update() calls racing the same resource are a frequent source of
+// 409s here).
+func UpdateMongoDBResource(mdb *mdbv1.MongoDB, updateFunc func(*mdbv1.MongoDB)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := f.Global.Client.Get(context.TODO(), types.NamespacedName{Name: mdb.Name, Namespace: mdb.Namespace}, mdb); err != nil {
+			return fmt.Errorf("error getting MongoDB resource: %s", err)
+		}
+		updateFunc(mdb)
+		return f.Global.Client.Update(context.TODO(), mdb)
+	})
+}